@@ -1,28 +1,61 @@
 // package meta is a extension for the goldmark(http://github.com/yuin/goldmark).
 //
-// This extension parses YAML metadata blocks and store metadata to a
-// parser.Context.
+// This extension parses YAML, TOML and JSON metadata blocks and store
+// metadata to a parser.Context.
 package meta
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"html"
+	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/yuin/goldmark"
 	gast "github.com/yuin/goldmark/ast"
 	east "github.com/yuin/goldmark/extension/ast"
 	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/text"
 	"github.com/yuin/goldmark/util"
 
+	"github.com/BurntSushi/toml"
 	"gopkg.in/yaml.v3"
 )
 
+// Format represents the front matter format that was used for a document.
+type Format int
+
+const (
+	// FormatYAML is a front matter delimited by "---" lines.
+	FormatYAML Format = iota
+	// FormatTOML is a front matter delimited by "+++" lines.
+	FormatTOML
+	// FormatJSON is a front matter enclosed in "{" and "}" lines.
+	FormatJSON
+)
+
 type data struct {
-	Map   map[string]interface{}
-	Items *yaml.Node
-	Error error
-	Node  gast.Node
+	Map    map[string]interface{}
+	Items  *yaml.Node
+	Typed  interface{}
+	Format Format
+	Raw    []byte
+	Error  error
+	Node   gast.Node
+}
+
+func decode(format Format, raw []byte, v interface{}) error {
+	switch format {
+	case FormatTOML:
+		return toml.Unmarshal(raw, v)
+	case FormatJSON:
+		return json.Unmarshal(raw, v)
+	default:
+		return yaml.Unmarshal(raw, v)
+	}
 }
 
 var contextKey = parser.NewContextKey()
@@ -51,6 +84,42 @@ func TryGet(pc parser.Context) (map[string]interface{}, error) {
 	return d.Map, nil
 }
 
+// GetFormat returns the front matter format that was used to decode the
+// document. It returns FormatYAML when the document has no front matter.
+func GetFormat(pc parser.Context) Format {
+	v := pc.Get(contextKey)
+	if v == nil {
+		return FormatYAML
+	}
+	d := v.(*data)
+	return d.Format
+}
+
+// GetTyped returns the metadata decoded into the struct passed to WithType.
+// It returns nil if WithType was not used.
+func GetTyped(pc parser.Context) interface{} {
+	v := pc.Get(contextKey)
+	if v == nil {
+		return nil
+	}
+	d := v.(*data)
+	return d.Typed
+}
+
+// Unmarshal decodes the document's front matter into v, using the decoder
+// that matches the format the front matter was written in.
+func Unmarshal(pc parser.Context, v interface{}) error {
+	dtmp := pc.Get(contextKey)
+	if dtmp == nil {
+		return nil
+	}
+	d := dtmp.(*data)
+	if d.Error != nil {
+		return d.Error
+	}
+	return decode(d.Format, d.Raw, v)
+}
+
 // GetItems returns a YAML metadata.
 // GetItems preserves defined key order.
 func GetItems(pc parser.Context) *yaml.Node {
@@ -77,20 +146,149 @@ func TryGetItems(pc parser.Context) (*yaml.Node, error) {
 	return d.Items, nil
 }
 
+// jsonBraceScanner tracks the running object-nesting depth of a JSON front
+// matter block, skipping over braces that appear inside (possibly escaped)
+// string values, so that a nested object's closing brace does not
+// prematurely close the block.
+type jsonBraceScanner struct {
+	depth    int
+	inString bool
+	escaped  bool
+}
+
+// scan advances the scanner over line and returns the offset within line
+// just past the brace that brought depth to zero, or -1 if depth has not
+// reached zero yet.
+func (s *jsonBraceScanner) scan(line []byte) int {
+	for i, c := range line {
+		if s.inString {
+			switch {
+			case s.escaped:
+				s.escaped = false
+			case c == '\\':
+				s.escaped = true
+			case c == '"':
+				s.inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			s.inString = true
+		case '{':
+			s.depth++
+		case '}':
+			s.depth--
+			if s.depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+// blockState carries the format detected by metaParser.Open through to
+// Continue and Close, plus the brace-depth scanner for FormatJSON.
+type blockState struct {
+	Format Format
+	JSON   *jsonBraceScanner
+}
+
+var blockStateKey = parser.NewContextKey()
+
+// Validator validates decoded front matter metadata.
+type Validator interface {
+	// Validate returns an error if meta does not satisfy the validator.
+	Validate(meta map[string]interface{}) error
+}
+
+type requiredKeysValidator struct {
+	keys []string
+}
+
+func (v *requiredKeysValidator) Validate(meta map[string]interface{}) error {
+	var missing []string
+	for _, k := range v.keys {
+		if _, ok := meta[k]; !ok {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("front matter is missing required key(s): %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// RequiredKeys returns a Validator that fails if any of keys is absent from
+// the front matter.
+func RequiredKeys(keys ...string) Validator {
+	return &requiredKeysValidator{keys: keys}
+}
+
+type typeSchemaValidator struct {
+	schema map[string]reflect.Kind
+}
+
+func (v *typeSchemaValidator) Validate(meta map[string]interface{}) error {
+	for key, kind := range v.schema {
+		val, ok := meta[key]
+		if !ok {
+			continue
+		}
+		if val == nil {
+			return fmt.Errorf("front matter key %q must be of kind %s, but got nil", key, kind)
+		}
+		if got := reflect.TypeOf(val).Kind(); got != kind {
+			return fmt.Errorf("front matter key %q must be of kind %s, but got %s", key, kind, got)
+		}
+	}
+	return nil
+}
+
+// TypeSchema returns a Validator that fails if a present front matter key's
+// value kind does not match schema. Keys that are absent are not checked.
+func TypeSchema(schema map[string]reflect.Kind) Validator {
+	return &typeSchemaValidator{schema: schema}
+}
+
+// Callback is invoked from metaParser.Close right after front matter has
+// been decoded and validated, before the AST transformer runs. An error
+// return populates data.Error the same way a decode error would.
+type Callback func(pc parser.Context, meta map[string]interface{}, items *yaml.Node) error
+
+// Mutator is like Callback, but its returned map replaces the metadata
+// stored for the document, so callers can normalize field names or coerce
+// values once at parse time.
+type Mutator func(pc parser.Context, meta map[string]interface{}, items *yaml.Node) (map[string]interface{}, error)
+
 type metaParser struct {
+	// Type, if set, is a prototype whose type front matter is additionally
+	// decoded into (as a new value) for every document, made available via
+	// GetTyped.
+	Type interface{}
+	// Validators, if set, are run against the decoded metadata map.
+	Validators []Validator
+	// Callback, if set, is run against the decoded metadata map.
+	Callback Callback
+	// Mutator, if set, replaces the decoded metadata map with its result.
+	Mutator Mutator
 }
 
 var defaultMetaParser = &metaParser{}
 
-// NewParser returns a BlockParser that can parse YAML metadata blocks.
+// NewParser returns a BlockParser that can parse YAML, TOML and JSON
+// metadata blocks.
 func NewParser() parser.BlockParser {
 	return defaultMetaParser
 }
 
-func isSeparator(line []byte) bool {
+func isSeparator(line []byte, c byte) bool {
 	line = util.TrimRightSpace(util.TrimLeftSpace(line))
+	if len(line) == 0 {
+		return false
+	}
 	for i := 0; i < len(line); i++ {
-		if line[i] != '-' {
+		if line[i] != c {
 			return false
 		}
 	}
@@ -98,7 +296,7 @@ func isSeparator(line []byte) bool {
 }
 
 func (b *metaParser) Trigger() []byte {
-	return []byte{'-'}
+	return []byte{'-', '+', '{'}
 }
 
 func (b *metaParser) Open(parent gast.Node, reader text.Reader, pc parser.Context) (gast.Node, parser.State) {
@@ -107,17 +305,43 @@ func (b *metaParser) Open(parent gast.Node, reader text.Reader, pc parser.Contex
 		return nil, parser.NoChildren
 	}
 	line, _ := reader.PeekLine()
-	if isSeparator(line) {
+	trimmed := util.TrimRightSpace(util.TrimLeftSpace(line))
+	switch {
+	case isSeparator(trimmed, '-'):
+		pc.Set(blockStateKey, &blockState{Format: FormatYAML})
+		return gast.NewTextBlock(), parser.NoChildren
+	case isSeparator(trimmed, '+'):
+		pc.Set(blockStateKey, &blockState{Format: FormatTOML})
+		return gast.NewTextBlock(), parser.NoChildren
+	case bytes.Equal(trimmed, []byte("{")):
+		pc.Set(blockStateKey, &blockState{Format: FormatJSON, JSON: &jsonBraceScanner{depth: 1}})
 		return gast.NewTextBlock(), parser.NoChildren
 	}
 	return nil, parser.NoChildren
 }
 
 func (b *metaParser) Continue(node gast.Node, reader text.Reader, pc parser.Context) parser.State {
+	st := pc.Get(blockStateKey).(*blockState)
 	line, segment := reader.PeekLine()
-	if isSeparator(line) && !util.IsBlank(line) {
-		reader.Advance(segment.Len())
-		return parser.Close
+	switch st.Format {
+	case FormatTOML:
+		trimmed := util.TrimRightSpace(util.TrimLeftSpace(line))
+		if isSeparator(trimmed, '+') && !util.IsBlank(line) {
+			reader.Advance(segment.Len())
+			return parser.Close
+		}
+	case FormatJSON:
+		if end := st.JSON.scan(line); end >= 0 {
+			node.Lines().Append(segment.WithStop(segment.Start + end))
+			reader.Advance(segment.Len())
+			return parser.Close
+		}
+	default:
+		trimmed := util.TrimRightSpace(util.TrimLeftSpace(line))
+		if isSeparator(trimmed, '-') && !util.IsBlank(line) {
+			reader.Advance(segment.Len())
+			return parser.Close
+		}
 	}
 	node.Lines().Append(segment)
 	return parser.Continue | parser.NoChildren
@@ -130,20 +354,86 @@ func (b *metaParser) Close(node gast.Node, reader text.Reader, pc parser.Context
 		segment := lines.At(i)
 		buf.Write(segment.Value(reader.Source()))
 	}
-	d := &data{}
+
+	st := pc.Get(blockStateKey).(*blockState)
+	format := st.Format
+	raw := buf.Bytes()
+	if format == FormatJSON {
+		// The opening "{" line is consumed by Open and never appended to
+		// Lines; the closing "}" is part of the last appended line (see
+		// jsonBraceScanner), so only the leading brace needs restoring.
+		raw = append([]byte{'{'}, buf.Bytes()...)
+	}
+
+	d := &data{Format: format, Raw: raw}
 	d.Node = node
+
 	meta := map[string]interface{}{}
-	if err := yaml.Unmarshal(buf.Bytes(), &meta); err != nil {
-		d.Error = err
-	} else {
+	d.Error = decode(format, raw, &meta)
+	if d.Error == nil {
+		for _, v := range b.Validators {
+			if err := v.Validate(meta); err != nil {
+				d.Error = err
+				break
+			}
+		}
+	}
+	if d.Error == nil {
 		d.Map = meta
 	}
 
+	if d.Error == nil && b.Type != nil {
+		typ := reflect.TypeOf(b.Type)
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		v := reflect.New(typ).Interface()
+		if err := decode(format, raw, v); err != nil {
+			d.Error = err
+		} else {
+			d.Typed = v
+		}
+	}
+
 	metaItems := &yaml.Node{}
-	if err := yaml.Unmarshal(buf.Bytes(), metaItems); err != nil {
-		d.Error = err
-	} else {
-		d.Items = metaItems
+	if format == FormatYAML {
+		if err := yaml.Unmarshal(buf.Bytes(), metaItems); err != nil {
+			d.Error = err
+		} else {
+			d.Items = metaItems
+		}
+	} else if d.Error == nil {
+		// TOML and JSON are decoded into a plain map above, so the ordered
+		// representation below is rebuilt from it and does not preserve the
+		// original key order.
+		if b2, err := yaml.Marshal(meta); err == nil {
+			if err := yaml.Unmarshal(b2, metaItems); err == nil {
+				d.Items = metaItems
+			}
+		}
+	}
+
+	if d.Error == nil && b.Callback != nil {
+		if err := b.Callback(pc, d.Map, d.Items); err != nil {
+			d.Error = err
+		}
+	}
+	if d.Error == nil && b.Mutator != nil {
+		newMap, err := b.Mutator(pc, d.Map, d.Items)
+		if err != nil {
+			d.Error = err
+		} else {
+			d.Map = newMap
+			// Keep GetItems (and the WithTable renderer, which reads it) in
+			// sync with the mutated map. Like the TOML/JSON items above,
+			// this rebuild does not preserve key order.
+			if b2, err := yaml.Marshal(newMap); err == nil {
+				newItems := &yaml.Node{}
+				if err := yaml.Unmarshal(b2, newItems); err == nil {
+					d.Items = newItems
+				}
+			}
+		}
 	}
 
 	pc.Set(contextKey, d)
@@ -191,61 +481,196 @@ func (a *astTransformer) Transform(node *gast.Document, reader text.Reader, pc p
 		return
 	}
 
-	table := east.NewTable()
-	alignments := []east.Alignment{}
-	for i := 1; i == len(meta.Content)%2; i++ {
-		alignments = append(alignments, east.AlignNone)
-	}
-	row := east.NewTableRow(alignments)
+	node.InsertBefore(node, node.FirstChild(), mappingNodeToTable(meta))
+}
+
+// mappingNodeToTable renders a YAML mapping node as a two-row table: a
+// header row of its keys and a row of their values. A key can steer its
+// column's alignment with a "!align=left|center|right" YAML tag, e.g.
+// "!align=right Price: 9.99".
+func mappingNodeToTable(meta *yaml.Node) *east.Table {
+	alignments := make([]east.Alignment, 0, len(meta.Content)/2)
+	keyNodes := make([]*yaml.Node, 0, len(meta.Content)/2)
 	valueNodes := make([]*yaml.Node, 0, len(meta.Content)/2)
 	for i := 0; i < len(meta.Content); i = i + 2 {
-		keyNode := meta.Content[i]
+		keyNodes = append(keyNodes, meta.Content[i])
 		valueNodes = append(valueNodes, meta.Content[i+1])
+		alignments = append(alignments, alignFromTag(meta.Content[i].Tag))
+	}
 
+	table := east.NewTable()
+
+	headerRow := east.NewTableRow(alignments)
+	for i, keyNode := range keyNodes {
 		cell := east.NewTableCell()
-		cell.AppendChild(cell, gast.NewString([]byte(valueNodeToString(keyNode))))
-		row.AppendChild(row, cell)
+		cell.Alignment = alignments[i]
+		cell.AppendChild(cell, gast.NewString([]byte(keyNode.Value)))
+		headerRow.AppendChild(headerRow, cell)
 	}
-	table.AppendChild(table, east.NewTableHeader(row))
+	table.AppendChild(table, east.NewTableHeader(headerRow))
 
-	row = east.NewTableRow(alignments)
-	for _, item := range valueNodes {
+	valueRow := east.NewTableRow(alignments)
+	for i, valueNode := range valueNodes {
 		cell := east.NewTableCell()
-		cell.AppendChild(cell, gast.NewString([]byte(valueNodeToString(item))))
-		row.AppendChild(row, cell)
+		cell.Alignment = alignments[i]
+		appendValueNode(cell, valueNode)
+		valueRow.AppendChild(valueRow, cell)
 	}
-	table.AppendChild(table, row)
-	node.InsertBefore(node, node.FirstChild(), table)
+	table.AppendChild(table, valueRow)
+
+	return table
 }
 
-func valueNodeToString(node *yaml.Node) string {
+// appendValueNode appends node's content to parent as goldmark AST nodes:
+// scalars (including booleans and numbers) become text in their native YAML
+// formatting, sequences become a list, and mappings recurse into a nested
+// table.
+func appendValueNode(parent gast.Node, node *yaml.Node) {
 	if node == nil {
-		return ""
+		return
 	}
 	switch node.Kind {
 	case yaml.SequenceNode:
-		val := make([]string, len(node.Content))
-		for i := range node.Content {
-			val[i] = valueNodeToString(node.Content[i])
+		list := gast.NewList('-')
+		list.IsTight = true
+		for _, item := range node.Content {
+			li := gast.NewListItem(2)
+			block := gast.NewTextBlock()
+			appendValueNode(block, item)
+			li.AppendChild(li, block)
+			list.AppendChild(list, li)
 		}
-		return fmt.Sprintf("%v", val)
+		parent.AppendChild(parent, list)
 
 	case yaml.MappingNode:
-		if (len(node.Content) % 2) != 0 {
-			return "<broken mapping node>"
+		parent.AppendChild(parent, mappingNodeToTable(node))
+
+	case yaml.ScalarNode:
+		parent.AppendChild(parent, gast.NewString([]byte(node.Value)))
+
+	default:
+		parent.AppendChild(parent, gast.NewString([]byte(fmt.Sprintf("<do not support yaml node kind '%v'>", node.Kind))))
+	}
+}
+
+func alignFromTag(tag string) east.Alignment {
+	switch strings.TrimPrefix(tag, "!align=") {
+	case "left":
+		return east.AlignLeft
+	case "right":
+		return east.AlignRight
+	case "center":
+		return east.AlignCenter
+	}
+	return east.AlignNone
+}
+
+// KindHTMLMeta is a NodeKind of the synthetic node inserted by WithHTMLMeta.
+var KindHTMLMeta = gast.NewNodeKind("HTMLMeta")
+
+// htmlMetaTag is a single <meta name="..." content="..."> to emit.
+type htmlMetaTag struct {
+	Name    string
+	Content string
+}
+
+// htmlMetaNode is a synthetic node holding the <title> and <meta> tags
+// derived from a document's front matter.
+type htmlMetaNode struct {
+	gast.BaseBlock
+	Title string
+	Tags  []htmlMetaTag
+}
+
+func (n *htmlMetaNode) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+func (n *htmlMetaNode) Kind() gast.NodeKind {
+	return KindHTMLMeta
+}
+
+// defaultHTMLMetaMapping is the front matter key to <meta> tag name mapping
+// used by WithHTMLMeta when no mapping is given explicitly.
+var defaultHTMLMetaMapping = map[string]string{
+	"Description": "description",
+	"Tags":        "keywords",
+	"Author":      "author",
+}
+
+func htmlMetaContentString(v interface{}) string {
+	switch vv := v.(type) {
+	case string:
+		return vv
+	case []interface{}:
+		parts := make([]string, 0, len(vv))
+		for _, item := range vv {
+			parts = append(parts, fmt.Sprint(item))
 		}
-		val := make(map[string]string, len(node.Content)%2)
-		for i := len(node.Content); i > 1; i = i - 2 {
-			k := valueNodeToString(node.Content[i-2])
-			val[fmt.Sprint(k)] = valueNodeToString(node.Content[i-1])
+		return strings.Join(parts, ", ")
+	default:
+		return fmt.Sprint(vv)
+	}
+}
+
+type htmlMetaTransformer struct {
+	Mapping map[string]string
+}
+
+func (a *htmlMetaTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	m := Get(pc)
+	if m == nil {
+		return
+	}
+
+	mn := &htmlMetaNode{}
+	if title, ok := m["Title"].(string); ok {
+		mn.Title = title
+	}
+
+	keys := make([]string, 0, len(a.Mapping))
+	for key := range a.Mapping {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		v, ok := m[key]
+		if !ok {
+			continue
 		}
-		return fmt.Sprintf("%v", val)
+		content := htmlMetaContentString(v)
+		if content == "" {
+			continue
+		}
+		mn.Tags = append(mn.Tags, htmlMetaTag{Name: a.Mapping[key], Content: content})
+	}
 
-	case yaml.ScalarNode:
-		return node.Value
+	if mn.Title == "" && len(mn.Tags) == 0 {
+		return
 	}
+	node.InsertBefore(node, node.FirstChild(), mn)
+}
 
-	return fmt.Sprintf("<do not support yaml node kind '%v'>", node.Kind)
+type htmlMetaHTMLRenderer struct{}
+
+func (r *htmlMetaHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindHTMLMeta, r.renderHTMLMeta)
+}
+
+func (r *htmlMetaHTMLRenderer) renderHTMLMeta(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkContinue, nil
+	}
+	mn := n.(*htmlMetaNode)
+	if mn.Title != "" {
+		_, _ = w.WriteString("<title>")
+		_, _ = w.WriteString(html.EscapeString(mn.Title))
+		_, _ = w.WriteString("</title>\n")
+	}
+	for _, tag := range mn.Tags {
+		_, _ = w.WriteString(fmt.Sprintf("<meta name=\"%s\" content=\"%s\">\n", html.EscapeString(tag.Name), html.EscapeString(tag.Content)))
+	}
+	return gast.WalkSkipChildren, nil
 }
 
 // Option is a functional option type for this extension.
@@ -258,8 +683,73 @@ func WithTable() Option {
 	}
 }
 
+// WithType is a functional option that decodes front matter into a new zero
+// value of the given prototype's type for every document, accessible via
+// GetTyped. Field values set on the prototype itself are not carried over.
+// prototype must be a pointer, e.g. WithType(&PostMeta{}).
+func WithType(prototype interface{}) Option {
+	return func(m *meta) {
+		m.Type = prototype
+	}
+}
+
+// WithValidators is a functional option that validates decoded front matter
+// metadata, surfacing failures through TryGet/TryGetItems the same way YAML
+// syntax errors are.
+func WithValidators(validators ...Validator) Option {
+	return func(m *meta) {
+		m.Validators = validators
+	}
+}
+
+// WithCallback is a functional option that runs cb against a document's
+// decoded front matter as soon as it is parsed, before the AST transformer
+// runs. An error returned by cb is surfaced through TryGet/TryGetItems the
+// same way a decode error would be.
+func WithCallback(cb Callback) Option {
+	return func(m *meta) {
+		m.Callback = cb
+	}
+}
+
+// WithMutator is a functional option that replaces a document's decoded
+// front matter with whatever mut returns, e.g. to normalize field names or
+// coerce values once at parse time. The replacement is reflected by Get,
+// TryGet, GetItems and TryGetItems. GetTyped is decoded via WithType before
+// mut runs and is not affected by it.
+func WithMutator(mut Mutator) Option {
+	return func(m *meta) {
+		m.Mutator = mut
+	}
+}
+
+// WithHTMLMeta is a functional option that emits the document's front
+// matter as <meta> tags (and a <title>) at the top of the rendered HTML,
+// using defaultHTMLMetaMapping unless overridden by WithHTMLMetaMapping.
+func WithHTMLMeta() Option {
+	return func(m *meta) {
+		m.HTMLMeta = true
+	}
+}
+
+// WithHTMLMetaMapping is like WithHTMLMeta, but maps front matter keys to
+// <meta> tag names itself, e.g. {"Description": "description"}. The "Title"
+// key is always promoted to <title> regardless of mapping.
+func WithHTMLMetaMapping(mapping map[string]string) Option {
+	return func(m *meta) {
+		m.HTMLMeta = true
+		m.HTMLMetaMapping = mapping
+	}
+}
+
 type meta struct {
-	Table bool
+	Table           bool
+	Type            interface{}
+	Validators      []Validator
+	Callback        Callback
+	Mutator         Mutator
+	HTMLMeta        bool
+	HTMLMetaMapping map[string]string
 }
 
 // Meta is a extension for the goldmark.
@@ -275,9 +765,13 @@ func New(opts ...Option) goldmark.Extender {
 }
 
 func (e *meta) Extend(m goldmark.Markdown) {
+	p := defaultMetaParser
+	if e.Type != nil || len(e.Validators) > 0 || e.Callback != nil || e.Mutator != nil {
+		p = &metaParser{Type: e.Type, Validators: e.Validators, Callback: e.Callback, Mutator: e.Mutator}
+	}
 	m.Parser().AddOptions(
 		parser.WithBlockParsers(
-			util.Prioritized(NewParser(), 0),
+			util.Prioritized(p, 0),
 		),
 	)
 	if e.Table {
@@ -287,4 +781,20 @@ func (e *meta) Extend(m goldmark.Markdown) {
 			),
 		)
 	}
+	if e.HTMLMeta {
+		mapping := e.HTMLMetaMapping
+		if mapping == nil {
+			mapping = defaultHTMLMetaMapping
+		}
+		m.Parser().AddOptions(
+			parser.WithASTTransformers(
+				util.Prioritized(&htmlMetaTransformer{Mapping: mapping}, 0),
+			),
+		)
+		m.Renderer().AddOptions(
+			renderer.WithNodeRenderers(
+				util.Prioritized(&htmlMetaHTMLRenderer{}, 0),
+			),
+		)
+	}
 }
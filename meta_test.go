@@ -2,12 +2,16 @@ package meta
 
 import (
 	"bytes"
+	"strings"
+	"testing"
+
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/util"
-	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestMeta(t *testing.T) {
@@ -59,6 +63,222 @@ Tags:
 	}
 }
 
+func TestMetaTOML(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Meta,
+		),
+	)
+	source := `+++
+Title = "goldmark-meta"
+Summary = "Add YAML metadata to the document"
++++
+
+# Hello goldmark-meta
+`
+
+	var buf bytes.Buffer
+	context := parser.NewContext()
+	if err := markdown.Convert([]byte(source), &buf, parser.WithContext(context)); err != nil {
+		panic(err)
+	}
+	if GetFormat(context) != FormatTOML {
+		t.Error("format must be FormatTOML")
+	}
+	metaData := Get(context)
+	if metaData["Title"] != "goldmark-meta" {
+		t.Errorf("Title must be %s, but got %v", "goldmark-meta", metaData["Title"])
+	}
+	if buf.String() != "<h1>Hello goldmark-meta</h1>\n" {
+		t.Errorf("should render '<h1>Hello goldmark-meta</h1>', but '%s'", buf.String())
+	}
+}
+
+func TestMetaJSON(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Meta,
+		),
+	)
+	source := `{
+"Title": "goldmark-meta",
+"Summary": "Add YAML metadata to the document"
+}
+
+# Hello goldmark-meta
+`
+
+	var buf bytes.Buffer
+	context := parser.NewContext()
+	if err := markdown.Convert([]byte(source), &buf, parser.WithContext(context)); err != nil {
+		panic(err)
+	}
+	if GetFormat(context) != FormatJSON {
+		t.Error("format must be FormatJSON")
+	}
+	metaData := Get(context)
+	if metaData["Title"] != "goldmark-meta" {
+		t.Errorf("Title must be %s, but got %v", "goldmark-meta", metaData["Title"])
+	}
+	if buf.String() != "<h1>Hello goldmark-meta</h1>\n" {
+		t.Errorf("should render '<h1>Hello goldmark-meta</h1>', but '%s'", buf.String())
+	}
+}
+
+type testPostMeta struct {
+	Title   string   `yaml:"Title"`
+	Summary string   `yaml:"Summary"`
+	Tags    []string `yaml:"Tags"`
+}
+
+func TestMetaWithType(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			New(WithType(&testPostMeta{})),
+		),
+	)
+	source := `---
+Title: goldmark-meta
+Summary: Add YAML metadata to the document
+Tags:
+    - markdown
+    - goldmark
+---
+
+# Hello goldmark-meta
+`
+
+	var buf bytes.Buffer
+	context := parser.NewContext()
+	if err := markdown.Convert([]byte(source), &buf, parser.WithContext(context)); err != nil {
+		panic(err)
+	}
+	typed, ok := GetTyped(context).(*testPostMeta)
+	if !ok {
+		t.Fatal("GetTyped must return *testPostMeta")
+	}
+	if typed.Title != "goldmark-meta" {
+		t.Errorf("Title must be %s, but got %v", "goldmark-meta", typed.Title)
+	}
+	if len(typed.Tags) != 2 || typed.Tags[0] != "markdown" || typed.Tags[1] != "goldmark" {
+		t.Errorf("Tags must be [markdown goldmark], but got %v", typed.Tags)
+	}
+
+	var other testPostMeta
+	if err := Unmarshal(context, &other); err != nil {
+		t.Fatal(err)
+	}
+	if other.Title != "goldmark-meta" {
+		t.Errorf("Title must be %s, but got %v", "goldmark-meta", other.Title)
+	}
+}
+
+func TestMetaWithValidators(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			New(WithValidators(RequiredKeys("Title", "Author"))),
+		),
+	)
+	source := `---
+Title: goldmark-meta
+Summary: Add YAML metadata to the document
+---
+
+# Hello goldmark-meta
+`
+
+	var buf bytes.Buffer
+	context := parser.NewContext()
+	if err := markdown.Convert([]byte(source), &buf, parser.WithContext(context)); err != nil {
+		panic(err)
+	}
+	if _, err := TryGet(context); err == nil {
+		t.Error("TryGet must return an error when a required key is missing")
+	}
+}
+
+func TestMetaWithCallbackAndMutator(t *testing.T) {
+	var callbackTitle string
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			New(
+				WithCallback(func(pc parser.Context, meta map[string]interface{}, items *yaml.Node) error {
+					callbackTitle, _ = meta["Title"].(string)
+					return nil
+				}),
+				WithMutator(func(pc parser.Context, meta map[string]interface{}, items *yaml.Node) (map[string]interface{}, error) {
+					meta["Slug"] = strings.ToLower(meta["Title"].(string))
+					return meta, nil
+				}),
+			),
+		),
+	)
+	source := `---
+Title: Hello World
+---
+
+# Hello goldmark-meta
+`
+
+	var buf bytes.Buffer
+	context := parser.NewContext()
+	if err := markdown.Convert([]byte(source), &buf, parser.WithContext(context)); err != nil {
+		panic(err)
+	}
+	if callbackTitle != "Hello World" {
+		t.Errorf("callback must observe Title %q, but got %q", "Hello World", callbackTitle)
+	}
+	metaData := Get(context)
+	if metaData["Slug"] != "hello world" {
+		t.Errorf("Slug must be %q, but got %v", "hello world", metaData["Slug"])
+	}
+
+	items := GetItems(context)
+	if items.Kind == yaml.DocumentNode {
+		items = items.Content[0]
+	}
+	found := false
+	for i := 0; i < len(items.Content); i += 2 {
+		if items.Content[i].Value == "Slug" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("GetItems must reflect the key added by the mutator")
+	}
+}
+
+func TestMetaWithHTMLMeta(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			New(WithHTMLMeta()),
+		),
+	)
+	source := `---
+Title: goldmark-meta
+Description: Add YAML metadata to the document
+Tags:
+    - markdown
+    - goldmark
+---
+
+# Hello goldmark-meta
+`
+
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(source), &buf); err != nil {
+		panic(err)
+	}
+	want := `<title>goldmark-meta</title>
+<meta name="description" content="Add YAML metadata to the document">
+<meta name="keywords" content="markdown, goldmark">
+<h1>Hello goldmark-meta</h1>
+`
+	if buf.String() != want {
+		t.Errorf("invalid HTML meta output, got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
 func TestMetaTable(t *testing.T) {
 	markdown := goldmark.New(
 		goldmark.WithExtensions(
@@ -97,7 +317,11 @@ Tags:
 <tr>
 <td>goldmark-meta</td>
 <td>Add YAML metadata to the document</td>
-<td>[markdown goldmark]</td>
+<td><ul>
+<li>markdown</li>
+<li>goldmark</li>
+</ul>
+</td>
 </tr>
 </tbody>
 </table>
@@ -107,6 +331,39 @@ Tags:
 	}
 }
 
+func TestMetaTableAlignAndNested(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			New(WithTable()),
+		),
+		goldmark.WithRendererOptions(
+			renderer.WithNodeRenderers(
+				util.Prioritized(extension.NewTableHTMLRenderer(), 500),
+			),
+		),
+	)
+	source := `---
+Title: goldmark-meta
+!align=right Price: 9.99
+Author:
+    Name: goldmark
+---
+
+# Hello goldmark-meta
+`
+
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(source), &buf); err != nil {
+		panic(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`<th style="text-align:right">Price</th>`)) {
+		t.Errorf("Price column must be right-aligned, got %s", buf.String())
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("<table>\n<thead>\n<tr>\n<th>Name</th>")) {
+		t.Errorf("Author must render as a nested table, got %s", buf.String())
+	}
+}
+
 func TestMetaError(t *testing.T) {
 	markdown := goldmark.New(
 		goldmark.WithExtensions(